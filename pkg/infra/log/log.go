@@ -0,0 +1,28 @@
+// Package log provides the structured logger used throughout pkg/. It is a thin
+// wrapper so call sites can depend on a small, stable interface regardless of the
+// logging backend wired in at startup.
+package log
+
+import (
+	golog "log"
+)
+
+// Logger writes leveled, structured log lines tagged with the name it was created with.
+type Logger struct {
+	name string
+}
+
+// New returns a Logger tagged with name, e.g. log.New("plugin.signature.validator").
+func New(name string) *Logger {
+	return &Logger{name: name}
+}
+
+func (l *Logger) Debug(msg string, ctx ...interface{}) { l.write("debug", msg, ctx...) }
+func (l *Logger) Info(msg string, ctx ...interface{})  { l.write("info", msg, ctx...) }
+func (l *Logger) Warn(msg string, ctx ...interface{})  { l.write("warn", msg, ctx...) }
+func (l *Logger) Error(msg string, ctx ...interface{}) { l.write("error", msg, ctx...) }
+
+func (l *Logger) write(level, msg string, ctx ...interface{}) {
+	fields := append([]interface{}{"logger", l.name, "msg", msg}, ctx...)
+	golog.Println(append([]interface{}{"level", level}, fields...)...)
+}
@@ -0,0 +1,91 @@
+// Package manager owns plugin discovery, loading, and the signature validation that
+// guards it.
+package manager
+
+import (
+	"context"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/manager/signature"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// PluginManager tracks loaded plugins and runs the signature Watcher against them for
+// the lifetime of the Grafana process.
+type PluginManager struct {
+	cfg       *setting.Cfg
+	validator signature.Validator
+	watcher   *signature.Watcher
+	bus       *signature.EventBus
+	reporter  signature.SubscribableReporter
+
+	mu      sync.Mutex
+	plugins map[string]*plugins.Plugin
+}
+
+// NewPluginManager wires a Validator, its Reporter, and its background Watcher
+// together. unsignedCond may be nil to fall back to cfg's global allowlist/Env escape
+// hatch.
+func NewPluginManager(cfg *setting.Cfg, unsignedCond signature.UnsignedPluginConditionFunc) *PluginManager {
+	m := &PluginManager{
+		cfg:      cfg,
+		bus:      signature.NewEventBus(),
+		reporter: signature.NewPubsubReporter(),
+		plugins:  map[string]*plugins.Plugin{},
+	}
+	m.validator = signature.NewValidator(cfg, unsignedCond, m.reporter)
+	m.watcher = signature.NewWatcher(cfg, &m.validator, m, m.bus)
+	return m
+}
+
+// Run starts the background signature watcher and blocks until ctx is cancelled.
+func (m *PluginManager) Run(ctx context.Context) {
+	m.watcher.Run(ctx, m.runningPlugins)
+}
+
+// SignatureEvents exposes the bus the Watcher publishes tampering violations to, so
+// admins can subscribe for alerting.
+func (m *PluginManager) SignatureEvents() *signature.EventBus {
+	return m.bus
+}
+
+// SignatureReporter exposes the Reporter every signature rejection is sent to, so e.g.
+// the /api/admin/plugins/signature-errors handler or a Prometheus collector can
+// subscribe and query recent errors without re-implementing the validator chain.
+func (m *PluginManager) SignatureReporter() signature.SubscribableReporter {
+	return m.reporter
+}
+
+// Load validates plugin and, if it passes, registers it as running.
+func (m *PluginManager) Load(plugin *plugins.Plugin) *plugins.PluginSignatureError {
+	if err := m.validator.Validate(plugin); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.plugins[plugin.ID] = plugin
+	m.watcher.NotifyLoaded(plugin.ID)
+	return nil
+}
+
+func (m *PluginManager) runningPlugins() []*plugins.Plugin {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]*plugins.Plugin, 0, len(m.plugins))
+	for _, p := range m.plugins {
+		out = append(out, p)
+	}
+	return out
+}
+
+// Unload implements signature.PluginUnloader, letting the Watcher remove a plugin whose
+// signature no longer validates.
+func (m *PluginManager) Unload(_ context.Context, pluginID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.plugins, pluginID)
+	return nil
+}
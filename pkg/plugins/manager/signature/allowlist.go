@@ -0,0 +1,70 @@
+package signature
+
+import (
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func init() {
+	Register("allowlist", func(cfg *setting.Cfg) SignatureValidator {
+		return &allowlistValidator{cfg: cfg}
+	})
+}
+
+// allowlistValidator is the fourth validator in the default chain. It only has an
+// opinion on plugins that signatureStateValidator left as SignatureUnsigned; every
+// other signature state was already resolved (accepted or rejected) upstream.
+type allowlistValidator struct {
+	cfg *setting.Cfg
+	// allowUnsignedPluginsCondition changes the policy for allowing unsigned plugins. Signature validation runs
+	// when plugins are starting and on Watcher rechecks, but the Watcher skips plugins without a readable
+	// MANIFEST.txt, so a running unsigned plugin without one is not terminated if it violates the new policy.
+	allowUnsignedPluginsCondition UnsignedPluginConditionFunc
+}
+
+func (*allowlistValidator) Name() string {
+	return "allowlist"
+}
+
+func (v *allowlistValidator) Validate(plugin *plugins.Plugin) *plugins.PluginSignatureError {
+	if plugin.Signature != plugins.SignatureUnsigned {
+		return nil
+	}
+
+	if plugin.IsCorePlugin() || plugin.IsBundledPlugin() {
+		return nil
+	}
+
+	if v.allowUnsigned(plugin) {
+		logger.Warn("Running an unsigned plugin", "pluginID", plugin.ID, "pluginDir", plugin.PluginDir)
+		return nil
+	}
+
+	logger.Debug("Plugin is unsigned", "pluginID", plugin.ID)
+	return &plugins.PluginSignatureError{
+		PluginID:        plugin.ID,
+		SignatureStatus: plugins.SignatureUnsigned,
+	}
+}
+
+func (v *allowlistValidator) allowUnsigned(plugin *plugins.Plugin) bool {
+	if allowed, set := allowUnsignedOverride(v.cfg, plugin); set {
+		return allowed
+	}
+
+	if v.allowUnsignedPluginsCondition != nil {
+		return v.allowUnsignedPluginsCondition(plugin)
+	}
+
+	if v.cfg.Env == setting.Dev {
+		return true
+	}
+
+	for _, plug := range v.cfg.PluginsAllowUnsigned {
+		if plug == plugin.ID {
+			return true
+		}
+	}
+
+	return false
+}
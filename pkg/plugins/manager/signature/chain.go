@@ -0,0 +1,113 @@
+package signature
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// SignatureValidator is a single check run against a plugin as part of the signature
+// validation Chain. Implementations must be safe to reuse across plugins and should not
+// mutate plugin state beyond what Validate already does (e.g. signature inheritance).
+type SignatureValidator interface {
+	// Name identifies the validator in configuration and logs, e.g. "signature_state".
+	Name() string
+	// Validate inspects plugin and returns a PluginSignatureError if the plugin fails
+	// this check, or nil if the plugin may proceed to the next validator in the chain.
+	Validate(plugin *plugins.Plugin) *plugins.PluginSignatureError
+}
+
+// Factory constructs a SignatureValidator for the given cfg. Validators that need no
+// configuration can ignore cfg entirely.
+type Factory func(cfg *setting.Cfg) SignatureValidator
+
+var registry = map[string]Factory{}
+
+// Register makes a validator factory available under name so it can be referenced from
+// the `signature_validators` setting. Register is typically called from an init function
+// of the file implementing the validator.
+func Register(name string, factory Factory) {
+	registry[name] = factory
+}
+
+// Chain runs a series of SignatureValidators in order, short-circuiting on the first
+// error, mirroring how the Kubernetes admission webhook chain evaluates plugins.
+type Chain struct {
+	validators []SignatureValidator
+}
+
+// NewChain builds a Chain from validators, preserving their order.
+func NewChain(validators ...SignatureValidator) *Chain {
+	return &Chain{validators: validators}
+}
+
+// Validate runs each validator in turn, returning the first non-nil error.
+func (c *Chain) Validate(plugin *plugins.Plugin) *plugins.PluginSignatureError {
+	for _, v := range c.validators {
+		if err := v.Validate(plugin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultValidatorNames is the order used when `signature_validators` is unset:
+//  1. per_plugin_policy - the `[plugin.<id>]` directives that don't depend on signature
+//     inheritance (pinned digest), a no-op unless such a section exists.
+//  2. signature_state - the historical Unsigned/Invalid/Modified checks and parent
+//     signature inheritance, populating SignatureType/SignatureOrg for nested plugins.
+//  3. per_plugin_signature_match - `required_signature_type`/`required_signature_org`;
+//     runs after signature_state so it sees the inherited values for nested plugins.
+//  4. allowlist - lets specific unsigned plugins (or Env == Dev) through.
+//  5. trust_policy - an additional, opt-in constraint on SignatureType/SignatureOrg,
+//     loaded from data/plugins/trustpolicy.json (or PluginSignatureTrustPolicy) and a
+//     no-op when that file doesn't exist or has no matching statement. It runs last so
+//     it narrows an already-accepted signature rather than widening a rejected one.
+var defaultValidatorNames = []string{
+	"per_plugin_policy", "signature_state", "per_plugin_signature_match", "allowlist", "trust_policy",
+}
+
+// buildChain resolves the ordered list of validator names from cfg (falling back to
+// defaultValidatorNames) into a Chain of registered validators.
+func buildChain(cfg *setting.Cfg, unsignedCond UnsignedPluginConditionFunc) (*Chain, error) {
+	names := defaultValidatorNames
+	if raw := cfg.PluginSignatureValidators; raw != "" {
+		names = parseValidatorNames(raw)
+	}
+	return buildChainFromNames(cfg, names, unsignedCond)
+}
+
+// parseValidatorNames splits a `signature_validators` value into trimmed, non-empty
+// names.
+func parseValidatorNames(raw string) []string {
+	var names []string
+	for _, n := range strings.Split(raw, ",") {
+		n = strings.TrimSpace(n)
+		if n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// buildChainFromNames resolves names into a Chain of registered validators, all
+// constructed against the real cfg (so a bad `signature_validators` value can be
+// recovered from by retrying with defaultValidatorNames without losing the rest of
+// cfg - DataPath, PluginSettings, Env, PluginsAllowUnsigned, etc.).
+func buildChainFromNames(cfg *setting.Cfg, names []string, unsignedCond UnsignedPluginConditionFunc) (*Chain, error) {
+	validators := make([]SignatureValidator, 0, len(names))
+	for _, name := range names {
+		factory, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown signature validator %q", name)
+		}
+		v := factory(cfg)
+		if sv, ok := v.(*allowlistValidator); ok {
+			sv.allowUnsignedPluginsCondition = unsignedCond
+		}
+		validators = append(validators, v)
+	}
+	return NewChain(validators...), nil
+}
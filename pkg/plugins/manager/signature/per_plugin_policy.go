@@ -0,0 +1,133 @@
+package signature
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Keys read from a `[plugin.<id>]` ini section via cfg.PluginSettings. These generalize
+// the global `allow_unsigned_plugins` allowlist and the `signature_validators` chain
+// into a per-plugin policy for fleets mixing first- and third-party plugins.
+//
+// pinned_manifest_sha256 is the plain sha256 of the plugin's MANIFEST.txt file, exactly
+// as `sha256sum MANIFEST.txt` reports it for the published plugin archive - operators
+// can compute the expected value without access to this codebase.
+const (
+	settingAllowUnsigned         = "allow_unsigned"
+	settingRequiredSignatureType = "required_signature_type"
+	settingRequiredSignatureOrg  = "required_signature_org"
+	settingPinnedManifestSHA256  = "pinned_manifest_sha256"
+)
+
+func init() {
+	factory := func(cfg *setting.Cfg) SignatureValidator {
+		return &perPluginPolicyValidator{cfg: cfg}
+	}
+	Register("per_plugin_policy", factory)
+	// digest_pinning is the name used in the `signature_validators` example in the
+	// request that introduced this validator; register it as an alias so that example
+	// doesn't hit the "unknown signature validator" fallback.
+	Register("digest_pinning", factory)
+
+	Register("per_plugin_signature_match", func(cfg *setting.Cfg) SignatureValidator {
+		return &perPluginSignatureMatchValidator{cfg: cfg}
+	})
+}
+
+// perPluginPolicyValidator enforces the `[plugin.<id>]` directives that don't depend on
+// signature inheritance from a parent plugin, ahead of the rest of the chain: a pinned
+// digest mismatch fails closed even for a plugin with an otherwise valid signature.
+// `allow_unsigned` is consulted separately, by allowUnsignedOverride, from the allowlist
+// validator itself.
+type perPluginPolicyValidator struct {
+	cfg *setting.Cfg
+}
+
+func (*perPluginPolicyValidator) Name() string {
+	return "per_plugin_policy"
+}
+
+func (v *perPluginPolicyValidator) Validate(plugin *plugins.Plugin) *plugins.PluginSignatureError {
+	settings, ok := v.cfg.PluginSettings[plugin.ID]
+	if !ok {
+		return nil
+	}
+
+	if pinned, ok := settings[settingPinnedManifestSHA256]; ok {
+		digest, err := rawManifestDigest(plugin.PluginDir)
+		if err != nil || !strings.EqualFold(digest, pinned) {
+			logger.Debug("Plugin MANIFEST.txt does not match pinned_manifest_sha256", "pluginID", plugin.ID, "error", err)
+			return &plugins.PluginSignatureError{
+				PluginID:        plugin.ID,
+				SignatureStatus: plugin.Signature,
+			}
+		}
+	}
+
+	return nil
+}
+
+// perPluginSignatureMatchValidator enforces `required_signature_type`/
+// `required_signature_org`. It runs after signature_state in defaultValidatorNames
+// because those fields are only populated for a nested plugin once signature_state has
+// inherited them from plugin.Parent; checking them any earlier would reject a
+// legitimately-signed child plugin whose own SignatureType/SignatureOrg are still unset.
+type perPluginSignatureMatchValidator struct {
+	cfg *setting.Cfg
+}
+
+func (*perPluginSignatureMatchValidator) Name() string {
+	return "per_plugin_signature_match"
+}
+
+func (v *perPluginSignatureMatchValidator) Validate(plugin *plugins.Plugin) *plugins.PluginSignatureError {
+	settings, ok := v.cfg.PluginSettings[plugin.ID]
+	if !ok {
+		return nil
+	}
+
+	if reqType, ok := settings[settingRequiredSignatureType]; ok && string(plugin.SignatureType) != reqType {
+		logger.Debug("Plugin signature type does not match required_signature_type", "pluginID", plugin.ID,
+			"signatureType", plugin.SignatureType, "required", reqType)
+		return &plugins.PluginSignatureError{
+			PluginID:        plugin.ID,
+			SignatureStatus: plugin.Signature,
+		}
+	}
+
+	if reqOrg, ok := settings[settingRequiredSignatureOrg]; ok && plugin.SignatureOrg != reqOrg {
+		logger.Debug("Plugin signature org does not match required_signature_org", "pluginID", plugin.ID,
+			"signatureOrg", plugin.SignatureOrg, "required", reqOrg)
+		return &plugins.PluginSignatureError{
+			PluginID:        plugin.ID,
+			SignatureStatus: plugin.Signature,
+		}
+	}
+
+	return nil
+}
+
+// allowUnsignedOverride returns the `[plugin.<id>] allow_unsigned` value for plugin and
+// whether a valid value is set. When set, true or false takes precedence over the global
+// allowlist and Env == Dev escape hatch.
+func allowUnsignedOverride(cfg *setting.Cfg, plugin *plugins.Plugin) (bool, bool) {
+	settings, ok := cfg.PluginSettings[plugin.ID]
+	if !ok {
+		return false, false
+	}
+
+	raw, ok := settings[settingAllowUnsigned]
+	if !ok {
+		return false, false
+	}
+
+	allowed, err := strconv.ParseBool(raw)
+	if err != nil {
+		logger.Warn("Invalid allow_unsigned value, ignoring", "pluginID", plugin.ID, "value", raw)
+		return false, false
+	}
+	return allowed, true
+}
@@ -0,0 +1,122 @@
+package signature
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func pluginWithManifest(t *testing.T, content string) *plugins.Plugin {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "MANIFEST.txt"), []byte(content), 0o600); err != nil {
+		t.Fatalf("writing MANIFEST.txt: %v", err)
+	}
+	return &plugins.Plugin{ID: "acme-datasource", PluginDir: dir, Signature: plugins.SignatureValid}
+}
+
+func TestPerPluginPolicyValidator_PinnedDigestMismatchFailsClosed(t *testing.T) {
+	plugin := pluginWithManifest(t, "manifest-v1")
+
+	cfg := &setting.Cfg{PluginSettings: setting.PluginSettings{
+		"acme-datasource": {settingPinnedManifestSHA256: "0000000000000000000000000000000000000000000000000000000000000000"},
+	}}
+	v := &perPluginPolicyValidator{cfg: cfg}
+
+	if err := v.Validate(plugin); err == nil {
+		t.Fatal("expected a pinned_manifest_sha256 mismatch to fail closed")
+	}
+}
+
+func TestPerPluginPolicyValidator_PinnedDigestMatchAllows(t *testing.T) {
+	plugin := pluginWithManifest(t, "manifest-v1")
+
+	digest, err := rawManifestDigest(plugin.PluginDir)
+	if err != nil {
+		t.Fatalf("rawManifestDigest: %v", err)
+	}
+
+	cfg := &setting.Cfg{PluginSettings: setting.PluginSettings{
+		"acme-datasource": {settingPinnedManifestSHA256: digest},
+	}}
+	v := &perPluginPolicyValidator{cfg: cfg}
+
+	if err := v.Validate(plugin); err != nil {
+		t.Fatalf("expected a matching pinned digest to pass, got error: %v", err)
+	}
+}
+
+func TestPerPluginPolicyValidator_UnreadableManifestFailsClosed(t *testing.T) {
+	plugin := &plugins.Plugin{ID: "acme-datasource", PluginDir: t.TempDir(), Signature: plugins.SignatureValid}
+
+	cfg := &setting.Cfg{PluginSettings: setting.PluginSettings{
+		"acme-datasource": {settingPinnedManifestSHA256: "deadbeef"},
+	}}
+	v := &perPluginPolicyValidator{cfg: cfg}
+
+	if err := v.Validate(plugin); err == nil {
+		t.Fatal("expected a missing MANIFEST.txt to fail closed when a digest is pinned")
+	}
+}
+
+func TestPerPluginPolicyValidator_NoSettingsIsNoOp(t *testing.T) {
+	plugin := pluginWithManifest(t, "manifest-v1")
+	v := &perPluginPolicyValidator{cfg: &setting.Cfg{}}
+
+	if err := v.Validate(plugin); err != nil {
+		t.Fatalf("expected no [plugin.<id>] section to be a no-op, got error: %v", err)
+	}
+}
+
+func TestPerPluginSignatureMatchValidator_RequiredOrgMismatchRejected(t *testing.T) {
+	cfg := &setting.Cfg{PluginSettings: setting.PluginSettings{
+		"acme-datasource": {settingRequiredSignatureOrg: "acme"},
+	}}
+	v := &perPluginSignatureMatchValidator{cfg: cfg}
+
+	plugin := &plugins.Plugin{ID: "acme-datasource", Signature: plugins.SignatureValid, SignatureOrg: "someoneelse"}
+	if err := v.Validate(plugin); err == nil {
+		t.Fatal("expected a SignatureOrg mismatch against required_signature_org to be rejected")
+	}
+}
+
+func TestPerPluginSignatureMatchValidator_RequiredTypeMatchAllows(t *testing.T) {
+	cfg := &setting.Cfg{PluginSettings: setting.PluginSettings{
+		"acme-datasource": {settingRequiredSignatureType: string(plugins.SignatureTypeCommercial)},
+	}}
+	v := &perPluginSignatureMatchValidator{cfg: cfg}
+
+	plugin := &plugins.Plugin{ID: "acme-datasource", Signature: plugins.SignatureValid, SignatureType: plugins.SignatureTypeCommercial}
+	if err := v.Validate(plugin); err != nil {
+		t.Fatalf("expected a matching required_signature_type to pass, got error: %v", err)
+	}
+}
+
+// TestChain_SignatureMatchSeesInheritedFields guards the ordering bug fixed earlier:
+// required_signature_type/org must be checked after signature_state has inherited those
+// fields from a parent plugin, not before.
+func TestChain_SignatureMatchSeesInheritedFields(t *testing.T) {
+	parent := &plugins.Plugin{
+		ID:            "acme-app",
+		Signature:     plugins.SignatureValid,
+		SignatureType: plugins.SignatureTypeCommercial,
+		SignatureOrg:  "acme",
+	}
+	child := &plugins.Plugin{ID: "acme-app-panel", Parent: parent}
+
+	cfg := &setting.Cfg{PluginSettings: setting.PluginSettings{
+		"acme-app-panel": {settingRequiredSignatureOrg: "acme"},
+	}}
+
+	chain, err := buildChainFromNames(cfg, defaultValidatorNames, nil)
+	if err != nil {
+		t.Fatalf("buildChainFromNames: %v", err)
+	}
+
+	if err := chain.Validate(child); err != nil {
+		t.Fatalf("expected the child plugin to inherit SignatureOrg before required_signature_org is checked, got error: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package signature
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/plugins"
+)
+
+// defaultReportRingSize is how many PluginSignatureReports pubsubReporter keeps per
+// plugin ID, enough for a support bundle to show recent history without unbounded growth.
+const defaultReportRingSize = 20
+
+// PluginSignatureReport is the structured record of a single signature rejection, with
+// enough context for an API handler or a Prometheus metric to explain "why is this
+// plugin disabled" without grepping logs.
+type PluginSignatureReport struct {
+	PluginID        string
+	PluginDir       string
+	SignatureStatus plugins.SignatureStatus
+	SignatureOrg    string
+	ParentPluginID  string
+	ReasonCode      string
+	Time            time.Time
+}
+
+// Reporter is notified every time Validate rejects a plugin. Implementations must not
+// block for long, since Report is called synchronously from Validate.
+type Reporter interface {
+	Report(ctx context.Context, err *plugins.PluginSignatureError, plugin *plugins.Plugin)
+}
+
+// SubscribableReporter is a Reporter that also exposes the query/subscription API the
+// default pubsubReporter implements, so an API handler like
+// /api/admin/plugins/signature-errors or a Prometheus collector can reach Subscribe and
+// Errors without depending on the unexported concrete type.
+type SubscribableReporter interface {
+	Reporter
+	// Subscribe registers fn to be called, in order, for every report.
+	Subscribe(fn func(PluginSignatureReport))
+	// Errors returns a copy of the most recent reports recorded for pluginID, oldest
+	// first.
+	Errors(pluginID string) []PluginSignatureReport
+}
+
+// pubsubReporter is the default Reporter: it keeps a bounded ring buffer of the last
+// reports per plugin ID and fans them out to subscribers, e.g. the
+// /api/admin/plugins/signature-errors handler or a Prometheus collector.
+type pubsubReporter struct {
+	mu       sync.Mutex
+	subs     []func(PluginSignatureReport)
+	ring     map[string][]PluginSignatureReport
+	ringSize int
+}
+
+// NewPubsubReporter creates a Reporter with no subscribers and an empty ring buffer.
+func NewPubsubReporter() *pubsubReporter {
+	return &pubsubReporter{
+		ring:     map[string][]PluginSignatureReport{},
+		ringSize: defaultReportRingSize,
+	}
+}
+
+// Subscribe registers fn to be called, in order, for every report.
+func (r *pubsubReporter) Subscribe(fn func(PluginSignatureReport)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs = append(r.subs, fn)
+}
+
+// Errors returns a copy of the most recent reports recorded for pluginID, oldest first.
+func (r *pubsubReporter) Errors(pluginID string) []PluginSignatureReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buf := r.ring[pluginID]
+	out := make([]PluginSignatureReport, len(buf))
+	copy(out, buf)
+	return out
+}
+
+func (r *pubsubReporter) Report(_ context.Context, err *plugins.PluginSignatureError, plugin *plugins.Plugin) {
+	report := PluginSignatureReport{
+		PluginID:        err.PluginID,
+		PluginDir:       plugin.PluginDir,
+		SignatureStatus: err.SignatureStatus,
+		SignatureOrg:    plugin.SignatureOrg,
+		ReasonCode:      reasonCode(err),
+		Time:            time.Now(),
+	}
+	if plugin.Parent != nil {
+		report.ParentPluginID = plugin.Parent.ID
+	}
+
+	r.mu.Lock()
+	buf := append(r.ring[report.PluginID], report)
+	if len(buf) > r.ringSize {
+		buf = buf[len(buf)-r.ringSize:]
+	}
+	r.ring[report.PluginID] = buf
+	subs := make([]func(PluginSignatureReport), len(r.subs))
+	copy(subs, r.subs)
+	r.mu.Unlock()
+
+	for _, fn := range subs {
+		fn(report)
+	}
+}
+
+func reasonCode(err *plugins.PluginSignatureError) string {
+	if err.SignatureStatus == "" {
+		return "unrecognized_signature_state"
+	}
+	return string(err.SignatureStatus)
+}
@@ -1,6 +1,8 @@
 package signature
 
 import (
+	"context"
+
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/setting"
@@ -8,96 +10,65 @@ import (
 
 var logger = log.New("plugin.signature.validator")
 
+// Validator is the entry point used by the plugin manager to decide whether a plugin is
+// allowed to load. It delegates to a Chain of SignatureValidators, resolved from the
+// `signature_validators` setting (or defaultValidatorNames if unset), so new checks can
+// be registered without changing callers of Validate. Every rejection is handed to a
+// Reporter so rejections are never silently dropped to the debug log.
 type Validator struct {
-	cfg *setting.Cfg
-	// allowUnsignedPluginsCondition changes the policy for allowing unsigned plugins. Signature validation only
-	// runs when plugins are starting, therefore running plugins will not be terminated if they violate the new policy.
-	allowUnsignedPluginsCondition UnsignedPluginConditionFunc
+	cfg      *setting.Cfg
+	chain    *Chain
+	reporter Reporter
 }
 
 type UnsignedPluginConditionFunc = func(plugin *plugins.Plugin) bool
 
-func NewValidator(cfg *setting.Cfg, unsignedCond UnsignedPluginConditionFunc) Validator {
-	return Validator{
-		cfg:                           cfg,
-		allowUnsignedPluginsCondition: unsignedCond,
-	}
-}
-
-func (s *Validator) Validate(plugin *plugins.Plugin) *plugins.PluginSignatureError {
-	if plugin.Signature == plugins.SignatureValid {
-		logger.Debug("Plugin has valid signature", "id", plugin.ID)
-		return nil
-	}
-
-	// If a plugin is nested within another, create links to each other to inherit signature details
-	if plugin.Parent != nil {
-		if plugin.IsCorePlugin() || plugin.Signature == plugins.SignatureInternal {
-			logger.Debug("Not setting descendant plugin's signature to that of root since it's core or internal",
-				"plugin", plugin.ID, "signature", plugin.Signature, "isCore", plugin.IsCorePlugin)
-		} else {
-			logger.Debug("Setting descendant plugin's signature to that of root", "plugin", plugin.ID,
-				"root", plugin.Parent.ID, "signature", plugin.Signature, "rootSignature", plugin.Parent.Signature)
-			plugin.Signature = plugin.Parent.Signature
-			plugin.SignatureType = plugin.Parent.SignatureType
-			plugin.SignatureOrg = plugin.Parent.SignatureOrg
-			if plugin.Signature == plugins.SignatureValid {
-				logger.Debug("Plugin has valid signature (inherited from root)", "id", plugin.ID)
-				return nil
-			}
-		}
+// NewValidator builds a Validator. reporter may be nil, in which case a pubsubReporter
+// is created so callers can still subscribe to it via Reporter().
+func NewValidator(cfg *setting.Cfg, unsignedCond UnsignedPluginConditionFunc, reporter Reporter) Validator {
+	chain, err := buildChain(cfg, unsignedCond)
+	if err != nil {
+		logger.Warn("Invalid signature_validators setting, falling back to the default validator order",
+			"error", err)
+		// Retry with the real cfg and only the *names* reset to the default order, so a
+		// typo in signature_validators can't silently blank out DataPath, PluginSettings,
+		// Env, or PluginsAllowUnsigned for every validator.
+		chain, _ = buildChainFromNames(cfg, defaultValidatorNames, unsignedCond)
 	}
 
-	if plugin.IsCorePlugin() || plugin.IsBundledPlugin() {
-		return nil
+	if reporter == nil {
+		reporter = NewPubsubReporter()
 	}
 
-	switch plugin.Signature {
-	case plugins.SignatureUnsigned:
-		if allowed := s.allowUnsigned(plugin); !allowed {
-			logger.Debug("Plugin is unsigned", "pluginID", plugin.ID)
-			return &plugins.PluginSignatureError{
-				PluginID:        plugin.ID,
-				SignatureStatus: plugins.SignatureUnsigned,
-			}
-		}
-		logger.Warn("Running an unsigned plugin", "pluginID", plugin.ID, "pluginDir", plugin.PluginDir)
-		return nil
-	case plugins.SignatureInvalid:
-		logger.Debug("Plugin has an invalid signature", "pluginID", plugin.ID)
-		return &plugins.PluginSignatureError{
-			PluginID:        plugin.ID,
-			SignatureStatus: plugins.SignatureInvalid,
-		}
-	case plugins.SignatureModified:
-		logger.Debug("Plugin has a modified signature", "pluginID", plugin.ID)
-		return &plugins.PluginSignatureError{
-			PluginID:        plugin.ID,
-			SignatureStatus: plugins.SignatureModified,
-		}
-	default:
-		logger.Debug("Plugin has an unrecognized plugin signature state", "pluginID", plugin.ID, "signature",
-			plugin.Signature)
-		return &plugins.PluginSignatureError{
-			PluginID: plugin.ID,
-		}
+	return Validator{
+		cfg:      cfg,
+		chain:    chain,
+		reporter: reporter,
 	}
 }
 
-func (s *Validator) allowUnsigned(plugin *plugins.Plugin) bool {
-	if s.allowUnsignedPluginsCondition != nil {
-		return s.allowUnsignedPluginsCondition(plugin)
-	}
+// Reporter returns the Validator's Reporter, so callers (e.g. an API handler or a
+// Prometheus collector) can subscribe to every rejection without re-implementing the
+// chain.
+func (s *Validator) Reporter() Reporter {
+	return s.reporter
+}
 
-	if s.cfg.Env == setting.Dev {
-		return true
+func (s *Validator) Validate(plugin *plugins.Plugin) *plugins.PluginSignatureError {
+	err := s.chain.Validate(plugin)
+	if err != nil {
+		s.reporter.Report(context.Background(), err, plugin)
+		return err
 	}
 
-	for _, plug := range s.cfg.PluginsAllowUnsigned {
-		if plug == plugin.ID {
-			return true
+	// Record the manifest digest the first time this plugin passes validation, so the
+	// Watcher has a baseline to diff later ticks against instead of treating every
+	// plugin as tampered on its first recheck.
+	if plugin.ManifestDigest == "" {
+		if digest, digestErr := computeManifestDigest(plugin); digestErr == nil {
+			plugin.ManifestDigest = digest
 		}
 	}
 
-	return false
+	return nil
 }
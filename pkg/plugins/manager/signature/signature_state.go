@@ -0,0 +1,76 @@
+package signature
+
+import (
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func init() {
+	Register("signature_state", func(cfg *setting.Cfg) SignatureValidator {
+		return &signatureStateValidator{}
+	})
+}
+
+// signatureStateValidator is the second validator in the default chain. It inherits
+// signature details from a parent plugin where applicable and rejects plugins whose
+// recorded Signature is anything other than SignatureValid or SignatureUnsigned (the
+// latter being handed off to the allowlist validator).
+type signatureStateValidator struct{}
+
+func (*signatureStateValidator) Name() string {
+	return "signature_state"
+}
+
+func (*signatureStateValidator) Validate(plugin *plugins.Plugin) *plugins.PluginSignatureError {
+	if plugin.Signature == plugins.SignatureValid {
+		logger.Debug("Plugin has valid signature", "id", plugin.ID)
+		return nil
+	}
+
+	// If a plugin is nested within another, create links to each other to inherit signature details
+	if plugin.Parent != nil {
+		if plugin.IsCorePlugin() || plugin.Signature == plugins.SignatureInternal {
+			logger.Debug("Not setting descendant plugin's signature to that of root since it's core or internal",
+				"plugin", plugin.ID, "signature", plugin.Signature, "isCore", plugin.IsCorePlugin)
+		} else {
+			logger.Debug("Setting descendant plugin's signature to that of root", "plugin", plugin.ID,
+				"root", plugin.Parent.ID, "signature", plugin.Signature, "rootSignature", plugin.Parent.Signature)
+			plugin.Signature = plugin.Parent.Signature
+			plugin.SignatureType = plugin.Parent.SignatureType
+			plugin.SignatureOrg = plugin.Parent.SignatureOrg
+			if plugin.Signature == plugins.SignatureValid {
+				logger.Debug("Plugin has valid signature (inherited from root)", "id", plugin.ID)
+				return nil
+			}
+		}
+	}
+
+	if plugin.IsCorePlugin() || plugin.IsBundledPlugin() {
+		return nil
+	}
+
+	switch plugin.Signature {
+	case plugins.SignatureUnsigned:
+		// Deferred to the allowlist validator, which decides whether an unsigned plugin
+		// is permitted to run.
+		return nil
+	case plugins.SignatureInvalid:
+		logger.Debug("Plugin has an invalid signature", "pluginID", plugin.ID)
+		return &plugins.PluginSignatureError{
+			PluginID:        plugin.ID,
+			SignatureStatus: plugins.SignatureInvalid,
+		}
+	case plugins.SignatureModified:
+		logger.Debug("Plugin has a modified signature", "pluginID", plugin.ID)
+		return &plugins.PluginSignatureError{
+			PluginID:        plugin.ID,
+			SignatureStatus: plugins.SignatureModified,
+		}
+	default:
+		logger.Debug("Plugin has an unrecognized plugin signature state", "pluginID", plugin.ID, "signature",
+			plugin.Signature)
+		return &plugins.PluginSignatureError{
+			PluginID: plugin.ID,
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package signature
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func init() {
+	Register("trust_policy", func(cfg *setting.Cfg) SignatureValidator {
+		path := cfg.PluginSignatureTrustPolicy
+		if path == "" {
+			path = filepath.Join(cfg.DataPath, "plugins", "trustpolicy.json")
+		}
+
+		policy, err := loadTrustPolicy(path)
+		if err != nil {
+			logger.Debug("No trust policy loaded, trustPolicy validator will allow every plugin", "path", path, "error", err)
+			policy = &trustPolicy{}
+		}
+
+		return &trustPolicyValidator{policy: policy}
+	})
+}
+
+// verificationLevel controls what a trustPolicyValidator does once it has found the
+// statement that applies to a plugin ID.
+type verificationLevel string
+
+const (
+	// verificationStrict rejects the plugin if it does not match the statement.
+	verificationStrict verificationLevel = "strict"
+	// verificationPermissive logs a warning but allows the plugin to continue.
+	verificationPermissive verificationLevel = "permissive"
+	// verificationAudit logs the violation as a warning but never blocks; it is not sent
+	// to a Reporter.
+	verificationAudit verificationLevel = "audit"
+	// verificationSkip bypasses the statement entirely.
+	verificationSkip verificationLevel = "skip"
+)
+
+// trustPolicyStatement is one rule of a trustpolicy.json document. PluginIDGlob is
+// matched with filepath.Match against the plugin ID.
+type trustPolicyStatement struct {
+	PluginIDGlob  string                  `json:"pluginIdGlob"`
+	SignatureType []plugins.SignatureType `json:"signatureTypes"`
+	SignatureOrgs []string                `json:"signatureOrgs"`
+	Verification  verificationLevel       `json:"signatureVerification"`
+}
+
+type trustPolicy struct {
+	Statements []trustPolicyStatement `json:"statements"`
+}
+
+func loadTrustPolicy(path string) (*trustPolicy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var p trustPolicy
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// statementFor returns the first statement whose PluginIDGlob matches pluginID, or nil
+// if no statement applies (in which case the plugin is left to the other validators).
+func (p *trustPolicy) statementFor(pluginID string) *trustPolicyStatement {
+	for i := range p.Statements {
+		if ok, _ := filepath.Match(p.Statements[i].PluginIDGlob, pluginID); ok {
+			return &p.Statements[i]
+		}
+	}
+	return nil
+}
+
+// trustPolicyValidator enforces a notation-style trust policy: a plugin with an
+// otherwise valid signature can still be rejected if its SignatureType/SignatureOrg
+// aren't in the set permitted for its plugin ID.
+type trustPolicyValidator struct {
+	policy *trustPolicy
+}
+
+func (*trustPolicyValidator) Name() string {
+	return "trust_policy"
+}
+
+func (v *trustPolicyValidator) Validate(plugin *plugins.Plugin) *plugins.PluginSignatureError {
+	stmt := v.policy.statementFor(plugin.ID)
+	if stmt == nil {
+		return nil
+	}
+
+	level := stmt.Verification
+	if level == "" {
+		level = verificationStrict
+	}
+
+	if level == verificationSkip {
+		return nil
+	}
+
+	if v.satisfies(stmt, plugin) {
+		return nil
+	}
+
+	err := &plugins.PluginSignatureError{
+		PluginID:        plugin.ID,
+		SignatureStatus: plugin.Signature,
+	}
+
+	switch level {
+	case verificationAudit:
+		logger.Warn("Plugin violates trust policy, allowing due to audit level", "pluginID", plugin.ID,
+			"signatureType", plugin.SignatureType, "signatureOrg", plugin.SignatureOrg)
+		return nil
+	case verificationPermissive:
+		logger.Warn("Plugin violates trust policy, allowing due to permissive level", "pluginID", plugin.ID,
+			"signatureType", plugin.SignatureType, "signatureOrg", plugin.SignatureOrg)
+		return nil
+	default:
+		logger.Debug("Plugin violates trust policy", "pluginID", plugin.ID, "signatureType", plugin.SignatureType,
+			"signatureOrg", plugin.SignatureOrg)
+		return err
+	}
+}
+
+func (v *trustPolicyValidator) satisfies(stmt *trustPolicyStatement, plugin *plugins.Plugin) bool {
+	if plugin.Signature != plugins.SignatureValid {
+		return false
+	}
+
+	if len(stmt.SignatureType) > 0 {
+		found := false
+		for _, t := range stmt.SignatureType {
+			if t == plugin.SignatureType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(stmt.SignatureOrgs) > 0 {
+		found := false
+		for _, org := range stmt.SignatureOrgs {
+			if org == string(plugin.SignatureOrg) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
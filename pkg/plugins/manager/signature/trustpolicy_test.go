@@ -0,0 +1,128 @@
+package signature
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/plugins"
+)
+
+func validPlugin(id string, sigType plugins.SignatureType, org string) *plugins.Plugin {
+	return &plugins.Plugin{
+		ID:            id,
+		Signature:     plugins.SignatureValid,
+		SignatureType: sigType,
+		SignatureOrg:  org,
+	}
+}
+
+func TestTrustPolicyValidator_NoMatchingStatementAllows(t *testing.T) {
+	v := &trustPolicyValidator{policy: &trustPolicy{Statements: []trustPolicyStatement{
+		{PluginIDGlob: "acme-*", SignatureOrgs: []string{"acme"}},
+	}}}
+
+	plugin := validPlugin("other-plugin", plugins.SignatureTypeCommercial, "someoneelse")
+	if err := v.Validate(plugin); err != nil {
+		t.Fatalf("expected no statement to match, got error: %v", err)
+	}
+}
+
+func TestTrustPolicyValidator_GlobMatchesAndRejectsWrongOrg(t *testing.T) {
+	v := &trustPolicyValidator{policy: &trustPolicy{Statements: []trustPolicyStatement{
+		{PluginIDGlob: "acme-*", SignatureOrgs: []string{"acme"}, Verification: verificationStrict},
+	}}}
+
+	plugin := validPlugin("acme-datasource", plugins.SignatureTypeCommercial, "someoneelse")
+	if err := v.Validate(plugin); err == nil {
+		t.Fatal("expected a plugin signed by the wrong org to be rejected")
+	}
+}
+
+func TestTrustPolicyValidator_GlobMatchesAndAllowsRightOrgAndType(t *testing.T) {
+	v := &trustPolicyValidator{policy: &trustPolicy{Statements: []trustPolicyStatement{
+		{
+			PluginIDGlob:  "acme-*",
+			SignatureType: []plugins.SignatureType{plugins.SignatureTypeCommercial},
+			SignatureOrgs: []string{"acme"},
+			Verification:  verificationStrict,
+		},
+	}}}
+
+	plugin := validPlugin("acme-datasource", plugins.SignatureTypeCommercial, "acme")
+	if err := v.Validate(plugin); err != nil {
+		t.Fatalf("expected a plugin matching type and org to be allowed, got error: %v", err)
+	}
+}
+
+func TestTrustPolicyValidator_WrongTypeRejected(t *testing.T) {
+	v := &trustPolicyValidator{policy: &trustPolicy{Statements: []trustPolicyStatement{
+		{
+			PluginIDGlob:  "acme-*",
+			SignatureType: []plugins.SignatureType{plugins.SignatureTypeGrafana},
+			Verification:  verificationStrict,
+		},
+	}}}
+
+	plugin := validPlugin("acme-datasource", plugins.SignatureTypeCommercial, "acme")
+	if err := v.Validate(plugin); err == nil {
+		t.Fatal("expected a plugin with the wrong signature type to be rejected")
+	}
+}
+
+func TestTrustPolicyValidator_PermissiveLevelAllowsDespiteMismatch(t *testing.T) {
+	v := &trustPolicyValidator{policy: &trustPolicy{Statements: []trustPolicyStatement{
+		{PluginIDGlob: "acme-*", SignatureOrgs: []string{"acme"}, Verification: verificationPermissive},
+	}}}
+
+	plugin := validPlugin("acme-datasource", plugins.SignatureTypeCommercial, "someoneelse")
+	if err := v.Validate(plugin); err != nil {
+		t.Fatalf("expected permissive level to allow a mismatching plugin, got error: %v", err)
+	}
+}
+
+func TestTrustPolicyValidator_AuditLevelAllowsDespiteMismatch(t *testing.T) {
+	v := &trustPolicyValidator{policy: &trustPolicy{Statements: []trustPolicyStatement{
+		{PluginIDGlob: "acme-*", SignatureOrgs: []string{"acme"}, Verification: verificationAudit},
+	}}}
+
+	plugin := validPlugin("acme-datasource", plugins.SignatureTypeCommercial, "someoneelse")
+	if err := v.Validate(plugin); err != nil {
+		t.Fatalf("expected audit level to allow a mismatching plugin, got error: %v", err)
+	}
+}
+
+func TestTrustPolicyValidator_SkipLevelBypassesStatement(t *testing.T) {
+	v := &trustPolicyValidator{policy: &trustPolicy{Statements: []trustPolicyStatement{
+		{PluginIDGlob: "acme-*", SignatureOrgs: []string{"acme"}, Verification: verificationSkip},
+	}}}
+
+	plugin := &plugins.Plugin{ID: "acme-datasource", Signature: plugins.SignatureUnsigned}
+	if err := v.Validate(plugin); err != nil {
+		t.Fatalf("expected skip level to bypass the statement entirely, got error: %v", err)
+	}
+}
+
+func TestTrustPolicyValidator_UnsignedPluginRejectedEvenWithMatchingOrg(t *testing.T) {
+	v := &trustPolicyValidator{policy: &trustPolicy{Statements: []trustPolicyStatement{
+		{PluginIDGlob: "acme-*", SignatureOrgs: []string{"acme"}, Verification: verificationStrict},
+	}}}
+
+	plugin := &plugins.Plugin{ID: "acme-datasource", Signature: plugins.SignatureUnsigned, SignatureOrg: "acme"}
+	if err := v.Validate(plugin); err == nil {
+		t.Fatal("expected an unsigned plugin to be rejected regardless of SignatureOrg")
+	}
+}
+
+func TestTrustPolicyStatementFor_FirstMatchWins(t *testing.T) {
+	policy := &trustPolicy{Statements: []trustPolicyStatement{
+		{PluginIDGlob: "acme-*", SignatureOrgs: []string{"first"}},
+		{PluginIDGlob: "acme-datasource", SignatureOrgs: []string{"second"}},
+	}}
+
+	stmt := policy.statementFor("acme-datasource")
+	if stmt == nil {
+		t.Fatal("expected a matching statement")
+	}
+	if stmt.SignatureOrgs[0] != "first" {
+		t.Fatalf("expected the first matching glob to win, got statement for org %q", stmt.SignatureOrgs[0])
+	}
+}
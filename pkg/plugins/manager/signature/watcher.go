@@ -0,0 +1,292 @@
+package signature
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// defaultRecheckInterval is used when `plugin_signature_recheck_interval` is unset or zero.
+const defaultRecheckInterval = 5 * time.Minute
+
+// defaultDebounce is the minimum time a plugin is skipped after it's been (re)loaded, so
+// a reload's on-disk churn isn't mistaken for tampering.
+const defaultDebounce = 30 * time.Second
+
+// PluginSignatureEvent is published whenever a Watcher re-validation finds that a
+// running plugin no longer satisfies the signature Validator.
+type PluginSignatureEvent struct {
+	Plugin *plugins.Plugin
+	Err    *plugins.PluginSignatureError
+	Time   time.Time
+}
+
+// EventBus lets admins subscribe to signature-violation events discovered after startup,
+// e.g. to page oncall or disable the offending plugin.
+type EventBus struct {
+	mu   sync.Mutex
+	subs []func(PluginSignatureEvent)
+}
+
+// NewEventBus creates an empty EventBus.
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe registers fn to be called, in order, for every published event.
+func (b *EventBus) Subscribe(fn func(PluginSignatureEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, fn)
+}
+
+func (b *EventBus) publish(evt PluginSignatureEvent) {
+	b.mu.Lock()
+	subs := make([]func(PluginSignatureEvent), len(b.subs))
+	copy(subs, b.subs)
+	b.mu.Unlock()
+
+	for _, fn := range subs {
+		fn(evt)
+	}
+}
+
+// PluginUnloader is the subset of the plugin registry that Watcher needs to remove a
+// plugin whose signature no longer validates.
+type PluginUnloader interface {
+	Unload(ctx context.Context, pluginID string) error
+}
+
+// Watcher periodically recomputes a running plugin's manifest digest and re-runs it
+// through a Validator, closing the gap noted on the original, startup-only Validate:
+// a plugin tampered with after it was loaded used to keep running undetected.
+type Watcher struct {
+	validator *Validator
+	interval  time.Duration
+	debounce  time.Duration
+	unloader  PluginUnloader
+	bus       *EventBus
+
+	mu         sync.Mutex
+	lastLoaded map[string]time.Time
+}
+
+// NewWatcher builds a Watcher from cfg's `plugin_signature_recheck_interval`. unloader
+// may be nil, in which case Watcher only reports violations without unloading plugins.
+func NewWatcher(cfg *setting.Cfg, validator *Validator, unloader PluginUnloader, bus *EventBus) *Watcher {
+	interval := cfg.PluginSignatureRecheckInterval
+	if interval <= 0 {
+		interval = defaultRecheckInterval
+	}
+
+	return &Watcher{
+		validator:  validator,
+		interval:   interval,
+		debounce:   defaultDebounce,
+		unloader:   unloader,
+		bus:        bus,
+		lastLoaded: map[string]time.Time{},
+	}
+}
+
+// NotifyLoaded resets the debounce window for pluginID, to be called whenever the
+// plugin manager (re)loads a plugin so its own writes to disk aren't flagged as
+// tampering on the next tick.
+func (w *Watcher) NotifyLoaded(pluginID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lastLoaded[pluginID] = time.Now()
+}
+
+// Run ticks every interval until ctx is cancelled, re-validating each plugin returned
+// by list.
+func (w *Watcher) Run(ctx context.Context, list func() []*plugins.Plugin) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, p := range list() {
+				w.recheck(ctx, p)
+			}
+		}
+	}
+}
+
+func (w *Watcher) recheck(ctx context.Context, plugin *plugins.Plugin) {
+	w.mu.Lock()
+	loadedAt, debouncing := w.lastLoaded[plugin.ID]
+	w.mu.Unlock()
+	if debouncing && time.Since(loadedAt) < w.debounce {
+		return
+	}
+
+	digest, err := computeManifestDigest(plugin)
+	if err != nil {
+		logger.Debug("Could not recompute manifest digest", "pluginID", plugin.ID, "error", err)
+		return
+	}
+
+	if plugin.ManifestDigest == "" {
+		// No digest was recorded when the plugin passed validation: record this one as
+		// the baseline to diff later ticks against.
+		plugin.ManifestDigest = digest
+		return
+	}
+
+	tampered := digest != plugin.ManifestDigest
+
+	// Re-run the full chain even when the files themselves haven't moved, so changes to
+	// the plugin's `[plugin.<id>]` settings in cfg are caught. The trust policy document
+	// is read once, when the Validator is built, so edits to it are not picked up here.
+	sigErr := w.validator.Validate(plugin)
+
+	if sigErr == nil && !tampered {
+		return
+	}
+
+	if sigErr == nil && tampered {
+		logger.Warn("Plugin files changed since load", "pluginID", plugin.ID)
+		plugin.Signature = plugins.SignatureModified
+		sigErr = &plugins.PluginSignatureError{
+			PluginID:        plugin.ID,
+			SignatureStatus: plugins.SignatureModified,
+		}
+	}
+
+	w.bus.publish(PluginSignatureEvent{Plugin: plugin, Err: sigErr, Time: time.Now()})
+
+	if w.unloader != nil {
+		if err := w.unloader.Unload(ctx, plugin.ID); err != nil {
+			logger.Error("Failed to unload plugin with invalid signature", "pluginID", plugin.ID, "error", err)
+		}
+	}
+}
+
+// computeManifestDigest re-hashes the files the plugin's MANIFEST.txt declares under
+// its "files" map against what's actually on disk right now, and combines the results
+// into a single, order-independent digest. Comparing two calls to this function (one at
+// load time, one from the Watcher) detects on-disk tampering; it does not by itself
+// prove the manifest's signature is still valid.
+func computeManifestDigest(plugin *plugins.Plugin) (string, error) {
+	manifestPath := filepath.Join(plugin.PluginDir, "MANIFEST.txt")
+	raw, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return "", err
+	}
+
+	files, err := manifestFiles(raw)
+	if err != nil {
+		return "", err
+	}
+
+	sums := make([]string, 0, len(files))
+	for relPath, declared := range files {
+		sum, err := hashFile(filepath.Join(plugin.PluginDir, relPath))
+		if err != nil {
+			return "", err
+		}
+		sums = append(sums, relPath+":"+sum+":"+declared)
+	}
+	sort.Strings(sums)
+
+	h := sha256.New()
+	for _, s := range sums {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// rawManifestDigest is the sha256 of MANIFEST.txt itself, exactly as `sha256sum
+// MANIFEST.txt` would report it. Unlike computeManifestDigest (which hashes the
+// plugin's other files), this is what an operator can reproduce from a published
+// plugin archive to pin against via `pinned_manifest_sha256`.
+func rawManifestDigest(pluginDir string) (string, error) {
+	sum, err := hashFile(filepath.Join(pluginDir, "MANIFEST.txt"))
+	if err != nil {
+		return "", err
+	}
+	return sum, nil
+}
+
+// manifestBody is the JSON payload clearsigned inside MANIFEST.txt.
+type manifestBody struct {
+	Files map[string]string `json:"files"`
+}
+
+// manifestFiles extracts the path->sha256 map grafana signs into MANIFEST.txt. The file
+// is a PGP clearsigned document: a "-----BEGIN PGP SIGNED MESSAGE-----" header, a
+// "Hash: ..." line, a blank line, the signed JSON body, then the detached signature.
+func manifestFiles(manifest []byte) (map[string]string, error) {
+	const msgHeader = "-----BEGIN PGP SIGNED MESSAGE-----"
+	const sigHeader = "-----BEGIN PGP SIGNATURE-----"
+
+	text := string(manifest)
+	start := strings.Index(text, msgHeader)
+	end := strings.Index(text, sigHeader)
+	if start == -1 || end == -1 || end <= start {
+		return nil, fmt.Errorf("not a clearsigned manifest")
+	}
+	// Skip past the header line's own newline, so the first line of body is the "Hash:"
+	// armor header rather than an empty string that would be mistaken for the blank line
+	// separating the armor headers from the signed payload.
+	headerLineEnd := strings.IndexByte(text[start:], '\n')
+	if headerLineEnd == -1 {
+		return nil, fmt.Errorf("not a clearsigned manifest")
+	}
+	body := text[start+headerLineEnd+1 : end]
+
+	var jsonLines []string
+	seenBlankLine := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !seenBlankLine {
+			// Skip the "Hash: ..." armor header(s) up to the blank line that
+			// separates them from the signed payload.
+			if trimmed == "" {
+				seenBlankLine = true
+			}
+			continue
+		}
+		if trimmed == "" {
+			continue
+		}
+		jsonLines = append(jsonLines, trimmed)
+	}
+
+	var parsed manifestBody
+	if err := json.Unmarshal([]byte(strings.Join(jsonLines, "\n")), &parsed); err != nil {
+		return nil, fmt.Errorf("parsing manifest payload: %w", err)
+	}
+	return parsed.Files, nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,147 @@
+package signature
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func clearsignedManifest(t *testing.T, filesJSON string) []byte {
+	t.Helper()
+	return []byte("-----BEGIN PGP SIGNED MESSAGE-----\n" +
+		"Hash: SHA512\n" +
+		"\n" +
+		filesJSON + "\n" +
+		"-----BEGIN PGP SIGNATURE-----\n" +
+		"iQEzBAEBCAAdFiEE...\n" +
+		"-----END PGP SIGNATURE-----\n")
+}
+
+func TestManifestFiles(t *testing.T) {
+	manifest := clearsignedManifest(t, `{"files":{"plugin.json":"abc123","img/logo.svg":"def456"}}`)
+
+	files, err := manifestFiles(manifest)
+	if err != nil {
+		t.Fatalf("manifestFiles returned error: %v", err)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d: %v", len(files), files)
+	}
+	if files["plugin.json"] != "abc123" {
+		t.Errorf("plugin.json digest = %q, want %q", files["plugin.json"], "abc123")
+	}
+	if files["img/logo.svg"] != "def456" {
+		t.Errorf("img/logo.svg digest = %q, want %q", files["img/logo.svg"], "def456")
+	}
+}
+
+func TestManifestFiles_NotClearsigned(t *testing.T) {
+	_, err := manifestFiles([]byte(`{"files":{"plugin.json":"abc123"}}`))
+	if err == nil {
+		t.Fatal("expected an error for a manifest with no PGP envelope, got nil")
+	}
+}
+
+func TestManifestFiles_MalformedPayload(t *testing.T) {
+	manifest := clearsignedManifest(t, `not valid json`)
+
+	_, err := manifestFiles(manifest)
+	if err == nil {
+		t.Fatal("expected an error for a non-JSON signed payload, got nil")
+	}
+}
+
+// writePlugin creates a plugin directory on disk with a MANIFEST.txt covering the given
+// extra files, and returns the plugin pointing at it.
+func writePlugin(t *testing.T, files map[string]string) *plugins.Plugin {
+	t.Helper()
+	dir := t.TempDir()
+
+	declared := map[string]string{}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+		sum, err := hashFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("hashing %s: %v", name, err)
+		}
+		declared[name] = sum
+	}
+
+	filesJSON := "{\"files\":{"
+	first := true
+	for name, sum := range declared {
+		if !first {
+			filesJSON += ","
+		}
+		first = false
+		filesJSON += "\"" + name + "\":\"" + sum + "\""
+	}
+	filesJSON += "}}"
+
+	manifest := clearsignedManifest(t, filesJSON)
+	if err := os.WriteFile(filepath.Join(dir, "MANIFEST.txt"), manifest, 0o600); err != nil {
+		t.Fatalf("writing MANIFEST.txt: %v", err)
+	}
+
+	return &plugins.Plugin{ID: "test-plugin", PluginDir: dir, Signature: plugins.SignatureValid}
+}
+
+func TestComputeManifestDigest_DetectsTampering(t *testing.T) {
+	plugin := writePlugin(t, map[string]string{"module.js": "console.log('v1')"})
+
+	before, err := computeManifestDigest(plugin)
+	if err != nil {
+		t.Fatalf("computeManifestDigest: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(plugin.PluginDir, "module.js"), []byte("console.log('tampered')"), 0o600); err != nil {
+		t.Fatalf("rewriting module.js: %v", err)
+	}
+
+	after, err := computeManifestDigest(plugin)
+	if err != nil {
+		t.Fatalf("computeManifestDigest after tampering: %v", err)
+	}
+
+	if before == after {
+		t.Fatal("expected the digest to change after a declared file was modified on disk")
+	}
+}
+
+func TestWatcherRecheck_PublishesOnTampering(t *testing.T) {
+	plugin := writePlugin(t, map[string]string{"module.js": "console.log('v1')"})
+
+	digest, err := computeManifestDigest(plugin)
+	if err != nil {
+		t.Fatalf("computeManifestDigest: %v", err)
+	}
+	plugin.ManifestDigest = digest
+
+	if err := os.WriteFile(filepath.Join(plugin.PluginDir, "module.js"), []byte("console.log('tampered')"), 0o600); err != nil {
+		t.Fatalf("rewriting module.js: %v", err)
+	}
+
+	validator := NewValidator(&setting.Cfg{}, nil, nil)
+	bus := NewEventBus()
+	watcher := &Watcher{validator: &validator, interval: 0, debounce: 0, bus: bus, lastLoaded: map[string]time.Time{}}
+
+	var published *PluginSignatureEvent
+	bus.Subscribe(func(evt PluginSignatureEvent) { published = &evt })
+
+	watcher.recheck(context.Background(), plugin)
+
+	if published == nil {
+		t.Fatal("expected a PluginSignatureEvent to be published after the plugin's files changed on disk")
+	}
+	if published.Err == nil || published.Err.SignatureStatus != plugins.SignatureModified {
+		t.Fatalf("expected SignatureModified, got %+v", published.Err)
+	}
+}
@@ -0,0 +1,75 @@
+// Package plugins models a Grafana plugin and its signature metadata.
+package plugins
+
+// SignatureStatus is the outcome of verifying a plugin's signature against its
+// MANIFEST.txt.
+type SignatureStatus string
+
+const (
+	SignatureInternal SignatureStatus = "internal"
+	SignatureValid    SignatureStatus = "valid"
+	SignatureInvalid  SignatureStatus = "invalid"
+	SignatureModified SignatureStatus = "modified"
+	SignatureUnsigned SignatureStatus = "unsigned"
+)
+
+// SignatureType identifies who is authorized to sign a given class of plugin.
+type SignatureType string
+
+const (
+	SignatureTypeGrafana    SignatureType = "grafana"
+	SignatureTypeCommercial SignatureType = "commercial"
+	SignatureTypeCommunity  SignatureType = "community"
+	SignatureTypePrivate    SignatureType = "private"
+)
+
+// Class describes where a plugin came from, which affects how strictly its signature
+// is enforced.
+type Class string
+
+const (
+	ClassCore     Class = "core"
+	ClassBundled  Class = "bundled"
+	ClassExternal Class = "external"
+)
+
+// PluginSignatureError explains why a plugin failed signature validation.
+type PluginSignatureError struct {
+	PluginID        string
+	SignatureStatus SignatureStatus
+}
+
+func (e *PluginSignatureError) Error() string {
+	if e.SignatureStatus != "" {
+		return "plugin " + e.PluginID + " failed signature check: " + string(e.SignatureStatus)
+	}
+	return "plugin " + e.PluginID + " failed signature check"
+}
+
+// Plugin is a loaded (or loading) plugin and the signature metadata recorded for it.
+type Plugin struct {
+	ID        string
+	PluginDir string
+	Class     Class
+
+	Signature     SignatureStatus
+	SignatureType SignatureType
+	SignatureOrg  string
+
+	// ManifestDigest is the digest computed over the plugin's on-disk files the first
+	// time it passed signature validation. The signature Watcher recomputes it
+	// periodically and compares against this recorded value to detect tampering.
+	ManifestDigest string
+
+	// Parent is set for plugins nested inside another (e.g. a panel bundled with a
+	// datasource), which inherit the parent's signature.
+	Parent *Plugin
+}
+
+func (p *Plugin) IsCorePlugin() bool {
+	return p.Class == ClassCore
+}
+
+func (p *Plugin) IsBundledPlugin() bool {
+	return p.Class == ClassBundled
+}
@@ -0,0 +1,38 @@
+// Package setting holds Grafana's parsed configuration.
+package setting
+
+import "time"
+
+// EnvType identifies the environment Grafana is running in (GF_DEFAULT_APP_MODE).
+type EnvType string
+
+// Dev is the development environment, used as an escape hatch for unsigned plugins.
+const Dev EnvType = "development"
+
+// PluginSettings holds the raw key/value pairs of every `[plugin.<id>]` ini section,
+// keyed by plugin ID. It backs both ordinary plugin app settings (e.g. `path`,
+// `enabled`) and the signature policy directives consulted by the signature validators.
+type PluginSettings map[string]map[string]string
+
+// Cfg is Grafana's parsed configuration. Only the fields the signature package depends
+// on are represented here.
+type Cfg struct {
+	Env      EnvType
+	DataPath string
+
+	PluginsAllowUnsigned []string
+	PluginSettings       PluginSettings
+
+	// PluginSignatureValidators is the comma-separated, ordered list of registered
+	// validator names to run, e.g. "signature_state,allowlist". Empty means use the
+	// package's default order.
+	PluginSignatureValidators string
+
+	// PluginSignatureTrustPolicy overrides the default
+	// `data/plugins/trustpolicy.json` location of the trust policy document.
+	PluginSignatureTrustPolicy string
+
+	// PluginSignatureRecheckInterval controls how often the signature Watcher re-hashes
+	// running plugins. Zero means use the package default.
+	PluginSignatureRecheckInterval time.Duration
+}